@@ -0,0 +1,103 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/infuseai/art/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var checkReadDataSubset string
+
+var checkCommand = &cobra.Command{
+	Use:   "check",
+	Short: "Verify the consistency of the repository",
+	Long: `Verify that every commit's manifest references blobs that still
+exist in the repository, and optionally re-download and re-hash a subset
+of blobs to detect bitrot. For example:
+
+# check that every referenced blob exists
+art check
+
+# also re-hash 5% of blobs
+art check --read-data-subset=5%`,
+	Run: check,
+}
+
+func check(cmd *cobra.Command, args []string) {
+	subset, err := parsePercentOrFraction(checkReadDataSubset)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "check: invalid --read-data-subset %q: %v\n", checkReadDataSubset, err)
+		os.Exit(1)
+	}
+
+	config, err := core.LoadConfig("")
+	if err != nil {
+		fmt.Printf("check %v \n", err)
+		return
+	}
+
+	mngr, err := core.NewArtifactManager(config)
+	if err != nil {
+		fmt.Printf("check %v \n", err)
+		return
+	}
+
+	errs := mngr.Check(core.CheckOptions{ReadDataSubset: subset})
+	for _, e := range errs {
+		fmt.Fprintln(os.Stderr, e.Error())
+	}
+
+	if len(errs) > 0 {
+		fmt.Printf("check found %d error(s)\n", len(errs))
+		os.Exit(1)
+	}
+	fmt.Println("check: repository is consistent")
+}
+
+// parsePercentOrFraction parses "5%" or "1/20" or "0.05" into a fraction
+// (0.05 for each of those). An empty string means 0. The result is not
+// checked to lie in [0, 1].
+func parsePercentOrFraction(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	if strings.HasSuffix(s, "%") {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil {
+			return 0, err
+		}
+		return v / 100, nil
+	}
+
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		n, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, err
+		}
+		d, err := strconv.ParseFloat(den, 64)
+		if err != nil {
+			return 0, err
+		}
+		if d == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return n / d, nil
+	}
+
+	return strconv.ParseFloat(s, 64)
+}
+
+func init() {
+	rootCmd.AddCommand(checkCommand)
+
+	checkCommand.Flags().StringVar(&checkReadDataSubset, "read-data-subset", "", "re-download and re-hash this fraction of blobs, e.g. 5% or 1/20")
+}
@@ -48,6 +48,13 @@ func list(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	// Tags are resolved the same way a raw version is: if ref names a
+	// tag, translate it to the commit it points at so "art list mytag"
+	// behaves exactly like "art list v1.0.0".
+	if tagged, err := mngr.ResolveTag(ref); err == nil {
+		ref = tagged
+	}
+
 	err = mngr.List(ref)
 	if err != nil {
 		fmt.Printf("list %v \n", err)
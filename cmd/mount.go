@@ -0,0 +1,66 @@
+//go:build linux || darwin
+
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/infuseai/art/internal/core"
+	"github.com/infuseai/art/internal/mount"
+	"github.com/spf13/cobra"
+)
+
+var mountCommand = &cobra.Command{
+	Use:   "mount <mountpoint>",
+	Short: "Mount the repository as a read-only filesystem",
+	Long: `Mount the repository as a read-only FUSE filesystem. Each
+top-level directory is a version ref ("latest", a version, or a tag), and beneath
+it the tree mirrors the committed artifact layout. For example:
+
+# mount the repository, browse it, then Ctrl-C or "umount" when done
+art mount /mnt/myrepo`,
+	Args: cobra.ExactArgs(1),
+	Run:  mount_,
+}
+
+func mount_(cmd *cobra.Command, args []string) {
+	mountpoint := args[0]
+
+	config, err := core.LoadConfig("")
+	if err != nil {
+		fmt.Printf("mount %v \n", err)
+		return
+	}
+
+	mngr, err := core.NewArtifactManager(config)
+	if err != nil {
+		fmt.Printf("mount %v \n", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		<-sig
+		cancel()
+	}()
+
+	fmt.Printf("mounted at %s, press Ctrl-C to unmount\n", mountpoint)
+	if err := mount.Mount(ctx, mngr, mountpoint); err != nil {
+		fmt.Printf("mount %v \n", err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(mountCommand)
+}
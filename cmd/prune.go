@@ -0,0 +1,129 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/infuseai/art/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var pruneOpts core.PruneOptions
+var pruneKeepWithin string
+
+var pruneCommand = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove unreferenced data from the repository",
+	Long: `Remove commits that fall outside the retention policy, then
+garbage-collect any blob that is no longer referenced by a retained
+commit. For example:
+
+# keep the 5 most recent commits, remove everything else
+art prune --keep-last 5
+
+# see what would be removed without deleting anything
+art prune --keep-last 5 --dry-run`,
+	Run: prune,
+}
+
+func prune(cmd *cobra.Command, args []string) {
+	if pruneKeepWithin != "" {
+		d, err := parseKeepWithin(pruneKeepWithin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "prune: invalid --keep-within duration %q: %v\n", pruneKeepWithin, err)
+			os.Exit(1)
+		}
+		pruneOpts.KeepWithin = d
+	}
+
+	config, err := core.LoadConfig("")
+	if err != nil {
+		fmt.Printf("prune %v \n", err)
+		return
+	}
+
+	mngr, err := core.NewArtifactManager(config)
+	if err != nil {
+		fmt.Printf("prune %v \n", err)
+		return
+	}
+
+	result, err := mngr.Prune(pruneOpts)
+	if err != nil {
+		fmt.Printf("prune %v \n", err)
+		return
+	}
+
+	for _, ref := range result.RemovedCommits {
+		fmt.Printf("remove commit %s\n", ref)
+	}
+	for _, key := range result.RemovedObjects {
+		fmt.Printf("remove %s\n", key)
+	}
+
+	if pruneOpts.DryRun {
+		fmt.Printf("%d commits and %d objects would be removed\n", len(result.RemovedCommits), len(result.RemovedObjects))
+	} else {
+		fmt.Printf("%d commits and %d objects removed\n", len(result.RemovedCommits), result.ReclaimedBlobs)
+	}
+}
+
+var keepWithinTermPattern = regexp.MustCompile(`(\d+)(y|d|h|m|s)`)
+
+// parseKeepWithin parses a restic-style duration such as "30d" or "1y6d",
+// which time.ParseDuration doesn't support on its own since its largest
+// unit is the hour, not days or years.
+func parseKeepWithin(s string) (time.Duration, error) {
+	terms := keepWithinTermPattern.FindAllStringSubmatch(s, -1)
+	if terms == nil {
+		return 0, fmt.Errorf("expected terms like \"30d\" or \"1y6d\"")
+	}
+
+	consumed := 0
+	var d time.Duration
+	for _, term := range terms {
+		consumed += len(term[0])
+
+		n, err := strconv.Atoi(term[1])
+		if err != nil {
+			return 0, err
+		}
+
+		switch term[2] {
+		case "y":
+			d += time.Duration(n) * 365 * 24 * time.Hour
+		case "d":
+			d += time.Duration(n) * 24 * time.Hour
+		default:
+			unitDuration, err := time.ParseDuration("1" + term[2])
+			if err != nil {
+				return 0, err
+			}
+			d += time.Duration(n) * unitDuration
+		}
+	}
+
+	if consumed != len(s) {
+		return 0, fmt.Errorf("expected terms like \"30d\" or \"1y6d\"")
+	}
+	return d, nil
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCommand)
+
+	pruneCommand.Flags().IntVar(&pruneOpts.KeepLast, "keep-last", 0, "keep the N most recent commits")
+	pruneCommand.Flags().IntVar(&pruneOpts.KeepDaily, "keep-daily", 0, "keep the most recent commit for the last N days")
+	pruneCommand.Flags().IntVar(&pruneOpts.KeepWeekly, "keep-weekly", 0, "keep the most recent commit for the last N weeks")
+	pruneCommand.Flags().IntVar(&pruneOpts.KeepMonthly, "keep-monthly", 0, "keep the most recent commit for the last N months")
+	pruneCommand.Flags().StringArrayVar(&pruneOpts.KeepTags, "keep-tag", nil, "keep commits with this tag (can be repeated)")
+	pruneCommand.Flags().StringVar(&pruneKeepWithin, "keep-within", "", "keep commits made within this duration, e.g. 30d")
+	pruneCommand.Flags().BoolVar(&pruneOpts.DryRun, "dry-run", false, "print what would be removed without removing it")
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/infuseai/art/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var pushCommand = &cobra.Command{
+	Use:   "push <path>",
+	Short: "Upload a file or directory as a new commit",
+	Long: `Upload a file or directory as a new commit. Files are split into
+content-defined chunks and packed into deduplicated blobs, so
+re-pushing a large dataset with small edits only transmits the chunks
+that changed. For example:
+
+art push ./dataset`,
+	Args: cobra.ExactArgs(1),
+	Run:  push,
+}
+
+func push(cmd *cobra.Command, args []string) {
+	config, err := core.LoadConfig("")
+	if err != nil {
+		fmt.Printf("push %v \n", err)
+		return
+	}
+
+	mngr, err := core.NewArtifactManager(config)
+	if err != nil {
+		fmt.Printf("push %v \n", err)
+		return
+	}
+
+	ref, err := mngr.Push(args[0])
+	if err != nil {
+		fmt.Printf("push %v \n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("pushed %s as commit %s\n", args[0], ref)
+}
+
+func init() {
+	rootCmd.AddCommand(pushCommand)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/infuseai/art/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var tagMessage string
+
+var tagCommand = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage tags",
+}
+
+var tagAddCommand = &cobra.Command{
+	Use:   "add <name> [ref]",
+	Short: "Tag a commit",
+	Long: `Tag a commit so it can be referenced by name. For example:
+
+# tag the latest commit
+art tag add v1.0.0
+
+# tag a specific commit
+art tag add v1.0.0 v0.9.3`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  tagAdd,
+}
+
+var tagRemoveCommand = &cobra.Command{
+	Use:     "rm <name>",
+	Aliases: []string{"remove"},
+	Short:   "Remove a tag",
+	Args:    cobra.ExactArgs(1),
+	Run:     tagRemove,
+}
+
+var tagListCommand = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List tags",
+	Args:    cobra.NoArgs,
+	Run:     tagList,
+}
+
+func tagAdd(cmd *cobra.Command, args []string) {
+	name := args[0]
+	ref := core.RefLatest
+	if len(args) == 2 {
+		ref = args[1]
+	}
+
+	config, err := core.LoadConfig("")
+	if err != nil {
+		fmt.Printf("tag %v \n", err)
+		return
+	}
+
+	mngr, err := core.NewArtifactManager(config)
+	if err != nil {
+		fmt.Printf("tag %v \n", err)
+		return
+	}
+
+	author := os.Getenv("USER")
+	if err := mngr.AddTag(name, ref, author, tagMessage); err != nil {
+		fmt.Printf("tag %v \n", err)
+	}
+}
+
+func tagRemove(cmd *cobra.Command, args []string) {
+	config, err := core.LoadConfig("")
+	if err != nil {
+		fmt.Printf("tag %v \n", err)
+		return
+	}
+
+	mngr, err := core.NewArtifactManager(config)
+	if err != nil {
+		fmt.Printf("tag %v \n", err)
+		return
+	}
+
+	if err := mngr.RemoveTag(args[0]); err != nil {
+		fmt.Printf("tag %v \n", err)
+	}
+}
+
+func tagList(cmd *cobra.Command, args []string) {
+	config, err := core.LoadConfig("")
+	if err != nil {
+		fmt.Printf("tag %v \n", err)
+		return
+	}
+
+	mngr, err := core.NewArtifactManager(config)
+	if err != nil {
+		fmt.Printf("tag %v \n", err)
+		return
+	}
+
+	tags, err := mngr.ListTags()
+	if err != nil {
+		fmt.Printf("tag %v \n", err)
+		return
+	}
+
+	for _, tag := range tags {
+		fmt.Printf("%s\t%s\t%s\n", tag.Name, tag.Ref, tag.Message)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(tagCommand)
+	tagCommand.AddCommand(tagAddCommand)
+	tagCommand.AddCommand(tagRemoveCommand)
+	tagCommand.AddCommand(tagListCommand)
+
+	tagAddCommand.Flags().StringVarP(&tagMessage, "message", "m", "", "optional tag message")
+}
@@ -0,0 +1,124 @@
+// Package chunker implements content-defined chunking using a rolling
+// buzhash. It is used by internal/core to split large artifact files into
+// variable-sized, content-addressed chunks so that re-uploading a dataset
+// with small edits only transmits the chunks that actually changed.
+package chunker
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"io"
+)
+
+const (
+	// MinSize is the smallest chunk the splitter will emit, except for the
+	// final chunk of a stream which may be shorter.
+	MinSize = 512 * 1024
+	// MaxSize is the largest chunk the splitter will ever emit, regardless
+	// of whether a chunk boundary was found by the rolling hash.
+	MaxSize = 8 * 1024 * 1024
+	// avgSize is the target average chunk size. The boundary mask is
+	// derived from it: a chunk boundary is declared once the rolling hash
+	// has `bits` trailing zero bits, which happens on average every
+	// 2^bits bytes.
+	avgSize = 1024 * 1024
+
+	windowSize = 64
+)
+
+// bits is the number of low bits of the rolling hash that must be zero to
+// mark a chunk boundary. log2(avgSize) = 20.
+const bits = 20
+const boundaryMask = (uint64(1) << bits) - 1
+
+// Chunk is a single content-defined chunk produced by a Splitter.
+type Chunk struct {
+	// Digest is the SHA-256 hash of Data, used as the chunk's content ID.
+	Digest [32]byte
+	// Data is the chunk's raw bytes.
+	Data []byte
+}
+
+// Splitter splits the bytes read from an io.Reader into content-defined
+// chunks using a rolling hash over a sliding window.
+type Splitter struct {
+	r      *bufio.Reader
+	window [windowSize]byte
+	pos    int
+	filled bool
+	hash   uint64
+}
+
+// New returns a Splitter that reads from r.
+func New(r io.Reader) *Splitter {
+	return &Splitter{r: bufio.NewReaderSize(r, MaxSize)}
+}
+
+// Next returns the next chunk, or io.EOF once the underlying reader is
+// exhausted.
+func (s *Splitter) Next() (Chunk, error) {
+	buf := make([]byte, 0, avgSize)
+
+	for {
+		b, err := s.r.ReadByte()
+		if err == io.EOF {
+			if len(buf) == 0 {
+				return Chunk{}, io.EOF
+			}
+			return newChunk(buf), nil
+		}
+		if err != nil {
+			return Chunk{}, err
+		}
+
+		buf = append(buf, b)
+		s.roll(b)
+
+		if len(buf) < MinSize {
+			continue
+		}
+		if len(buf) >= MaxSize {
+			return newChunk(buf), nil
+		}
+		if s.filled && s.hash&boundaryMask == 0 {
+			return newChunk(buf), nil
+		}
+	}
+}
+
+// roll updates the rolling buzhash with the next input byte, evicting the
+// byte that is sliding out of the window.
+func (s *Splitter) roll(b byte) {
+	out := s.window[s.pos]
+	s.window[s.pos] = b
+	s.pos = (s.pos + 1) % windowSize
+	if s.pos == 0 {
+		s.filled = true
+	}
+
+	s.hash = rol1(s.hash) ^ rol(table[out], windowSize) ^ uint64(table[b])
+}
+
+func newChunk(buf []byte) Chunk {
+	return Chunk{Digest: sha256.Sum256(buf), Data: buf}
+}
+
+func rol1(v uint64) uint64 { return rol(v, 1) }
+
+func rol(v uint64, n uint) uint64 {
+	return (v << (n % 64)) | (v >> (64 - n%64))
+}
+
+// table is a fixed pseudo-random permutation used by the buzhash, one
+// 64-bit value per possible byte.
+var table = func() [256]uint64 {
+	var t [256]uint64
+	seed := uint64(0x9e3779b97f4a7c15)
+	for i := range t {
+		seed ^= seed << 13
+		seed ^= seed >> 7
+		seed ^= seed << 17
+		t[i] = seed
+	}
+	return t
+}()
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// commitsPrefix is the repository prefix under which every commit's
+// manifest is stored, one directory per commit id.
+const commitsPrefix = "commits"
+
+// commitManifestPath returns where a commit's manifest.json lives.
+func commitManifestPath(ref string) string {
+	return fmt.Sprintf("%s/%s/manifest.json", commitsPrefix, ref)
+}
+
+// commitInfo is the minimal metadata Prune/Check need about a commit,
+// without loading its (potentially large) manifest.
+type commitInfo struct {
+	Ref       string
+	Timestamp time.Time
+}
+
+// listCommits returns every commit in the repository, newest first.
+// Commit ids are monotonically increasing UnixNano timestamps (see
+// Push), so a plain descending sort by id is enough to get newest-first
+// order without having to read every manifest up front.
+func (m *ArtifactManager) listCommits() ([]commitInfo, error) {
+	entries, err := m.repo.List(commitsPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]commitInfo, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		ref := entry.Name()
+		ts, err := strconv.ParseInt(ref, 10, 64)
+		if err != nil {
+			continue
+		}
+		commits = append(commits, commitInfo{Ref: ref, Timestamp: time.Unix(0, ts)})
+	}
+
+	sort.Slice(commits, func(i, j int) bool { return commits[i].Timestamp.After(commits[j].Timestamp) })
+	return commits, nil
+}
+
+// loadManifest downloads and parses the manifest for ref.
+func (m *ArtifactManager) loadManifest(ref string) (*Manifest, error) {
+	tmp, err := os.CreateTemp("", "art-manifest-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	tmp.Close()
+
+	if err := m.repo.Download(commitManifestPath(ref), tmp.Name(), nil); err != nil {
+		return nil, fmt.Errorf("loading manifest for %s: %w", ref, err)
+	}
+
+	data, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing manifest for %s: %w", ref, err)
+	}
+	return &manifest, nil
+}
+
+// ResolveRef resolves ref - RefLatest, a tag name, or a raw commit id -
+// to the commit id it refers to.
+func (m *ArtifactManager) ResolveRef(ref string) (string, error) {
+	if ref == RefLatest {
+		commits, err := m.listCommits()
+		if err != nil {
+			return "", err
+		}
+		if len(commits) == 0 {
+			return "", fmt.Errorf("repository has no commits")
+		}
+		return commits[0].Ref, nil
+	}
+
+	if tagged, err := m.ResolveTag(ref); err == nil {
+		return tagged, nil
+	}
+
+	if _, err := m.repo.Stat(commitManifestPath(ref)); err != nil {
+		return "", fmt.Errorf("no such ref %q", ref)
+	}
+	return ref, nil
+}
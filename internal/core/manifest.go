@@ -0,0 +1,13 @@
+package core
+
+// ManifestFile is one file within a commit's manifest: the repo-relative
+// path it was uploaded to, plus the chunks that reconstruct it.
+type ManifestFile struct {
+	Path string `json:"path"`
+	FileManifest
+}
+
+// Manifest is the full set of files recorded by a single commit.
+type Manifest struct {
+	Files []ManifestFile `json:"files"`
+}
@@ -0,0 +1,304 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/infuseai/art/internal/chunker"
+	"github.com/infuseai/art/internal/repository"
+)
+
+// packSizeLimit is the target size of a single pack blob under data/xx/...
+// in the repository. Chunks are accumulated into a pack until it reaches
+// this size, then the pack is flushed as one upload.
+const packSizeLimit = 16 * 1024 * 1024
+
+// chunkIndexPath is where the digest -> pack location index is kept, so
+// that re-uploading a dataset with small edits can skip chunks that are
+// already present in the repository.
+const chunkIndexPath = "index/chunks.json"
+
+// ChunkRef locates a single chunk inside a pack blob.
+type ChunkRef struct {
+	Digest string `json:"digest"`
+	Pack   string `json:"pack"`
+	Offset int64  `json:"offset"`
+	Length int64  `json:"length"`
+}
+
+// FileManifest is the chunked representation of one uploaded file: the
+// ordered list of chunks that, concatenated, reproduce the file.
+type FileManifest struct {
+	Chunks []ChunkRef `json:"chunks"`
+}
+
+type chunkIndex map[string]ChunkRef
+
+func loadChunkIndex(repo repository.Repository) (chunkIndex, error) {
+	idx := chunkIndex{}
+
+	tmp, err := os.CreateTemp("", "art-chunk-index-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := repo.Download(chunkIndexPath, tmp.Name(), nil); err != nil {
+		// No index yet; start from an empty one.
+		return idx, nil
+	}
+
+	data, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return idx, nil
+	}
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+func (idx chunkIndex) save(repo repository.Repository) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "art-chunk-index-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	tmp.Close()
+
+	return repo.Upload(tmp.Name(), chunkIndexPath, nil)
+}
+
+// blobPath returns the "data/xx/xxxxx..." location for a pack with the
+// given id, fanning out into 256 subdirectories to keep any one directory
+// from growing too large.
+func blobPath(packID string) string {
+	return fmt.Sprintf("data/%s/%s", packID[:2], packID)
+}
+
+// chunkSlot identifies one chunk reference inside a particular file's
+// manifest, so a pack session can patch in the real pack id once a pack
+// spanning multiple files is actually uploaded.
+type chunkSlot struct {
+	manifest *FileManifest
+	index    int
+}
+
+// packSession accumulates chunks from, potentially, many files into
+// shared ~16MB packs and a single chunk index, so that pushing a tree of
+// many small files doesn't flush one pack (and reload/resave the whole
+// index) per file. Create one with newPackSession per push, call
+// uploadFile once per file, and Close it once at the end.
+type packSession struct {
+	repo  repository.Repository
+	index chunkIndex
+
+	pack     bytes.Buffer
+	packRefs []ChunkRef
+
+	// pending holds chunks that have already been written into the
+	// currently-open (not yet uploaded) pack, keyed by digest. Without
+	// this, a chunk digest seen twice before the pack is flushed would
+	// look "new" both times (it's not yet in the persisted index) and
+	// get written into the pack twice.
+	pending map[string]ChunkRef
+	// unresolved tracks, per digest, the manifest slots that were
+	// appended before the pack holding that digest was uploaded, so their
+	// Pack field can be filled in once the real pack id is known.
+	unresolved map[string][]chunkSlot
+}
+
+func newPackSession(repo repository.Repository) (*packSession, error) {
+	index, err := loadChunkIndex(repo)
+	if err != nil {
+		return nil, fmt.Errorf("newPackSession: %w", err)
+	}
+	return &packSession{
+		repo:       repo,
+		index:      index,
+		pending:    map[string]ChunkRef{},
+		unresolved: map[string][]chunkSlot{},
+	}, nil
+}
+
+func (s *packSession) flush() error {
+	if s.pack.Len() == 0 {
+		return nil
+	}
+	packID := fmt.Sprintf("%x", sha256Sum(s.pack.Bytes()))
+
+	tmp, err := os.CreateTemp("", "art-pack-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(s.pack.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	tmp.Close()
+
+	if err := s.repo.Upload(tmp.Name(), blobPath(packID), nil); err != nil {
+		return err
+	}
+
+	for _, ref := range s.packRefs {
+		ref.Pack = packID
+		s.index[ref.Digest] = ref
+		for _, slot := range s.unresolved[ref.Digest] {
+			slot.manifest.Chunks[slot.index].Pack = packID
+		}
+		delete(s.unresolved, ref.Digest)
+	}
+
+	s.pack.Reset()
+	s.packRefs = nil
+	s.pending = map[string]ChunkRef{}
+	return nil
+}
+
+// uploadFile splits the file at path into content-defined chunks and
+// appends the ones not already known to the session's shared pack,
+// flushing it as a new blob whenever it reaches packSizeLimit.
+func (s *packSession) uploadFile(path string) (*FileManifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	manifest := &FileManifest{}
+	split := chunker.New(f)
+
+	for {
+		chunk, err := split.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		digest := hex.EncodeToString(chunk.Digest[:])
+
+		if ref, ok := s.index[digest]; ok {
+			manifest.Chunks = append(manifest.Chunks, ref)
+			continue
+		}
+
+		if ref, ok := s.pending[digest]; ok {
+			manifest.Chunks = append(manifest.Chunks, ref)
+			s.unresolved[digest] = append(s.unresolved[digest], chunkSlot{manifest, len(manifest.Chunks) - 1})
+			continue
+		}
+
+		ref := ChunkRef{Digest: digest, Offset: int64(s.pack.Len()), Length: int64(len(chunk.Data))}
+		s.pack.Write(chunk.Data)
+		s.packRefs = append(s.packRefs, ref)
+		s.pending[digest] = ref
+
+		manifest.Chunks = append(manifest.Chunks, ref)
+		s.unresolved[digest] = append(s.unresolved[digest], chunkSlot{manifest, len(manifest.Chunks) - 1})
+
+		if s.pack.Len() >= packSizeLimit {
+			if err := s.flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return manifest, nil
+}
+
+// Close flushes any partial pack and persists the chunk index. It must
+// be called once, after every file in the session has been uploaded.
+func (s *packSession) Close() error {
+	if err := s.flush(); err != nil {
+		return err
+	}
+	return s.index.save(s.repo)
+}
+
+// UploadChunked splits the file at path into content-defined chunks,
+// packs the chunks that are not already present in the repository into
+// ~16MB blobs under data/xx/..., and returns the ordered chunk references
+// that make up the file. Uploading several files this way, one call
+// each, produces one pack per file; to consolidate chunks from many
+// files into shared packs, use a single packSession across all of them
+// instead (see Push).
+func UploadChunked(repo repository.Repository, path string) (*FileManifest, error) {
+	session, err := newPackSession(repo)
+	if err != nil {
+		return nil, fmt.Errorf("UploadChunked: %w", err)
+	}
+
+	manifest, err := session.uploadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := session.Close(); err != nil {
+		return nil, fmt.Errorf("UploadChunked: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// DownloadChunked reconstructs a file at dest from the chunk references in
+// manifest, fetching each referenced pack blob from the repository.
+func DownloadChunked(repo repository.Repository, manifest *FileManifest, dest string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	packCache := map[string][]byte{}
+	for _, ref := range manifest.Chunks {
+		data, ok := packCache[ref.Pack]
+		if !ok {
+			tmp, err := os.CreateTemp("", "art-pack-*")
+			if err != nil {
+				return err
+			}
+			defer os.Remove(tmp.Name())
+			tmp.Close()
+
+			if err := repo.Download(blobPath(ref.Pack), tmp.Name(), nil); err != nil {
+				return fmt.Errorf("DownloadChunked: fetching pack %s: %w", ref.Pack, err)
+			}
+			data, err = os.ReadFile(tmp.Name())
+			if err != nil {
+				return err
+			}
+			packCache[ref.Pack] = data
+		}
+
+		if ref.Offset+ref.Length > int64(len(data)) {
+			return fmt.Errorf("DownloadChunked: chunk %s out of range of pack %s", ref.Digest, ref.Pack)
+		}
+		if _, err := out.Write(data[ref.Offset : ref.Offset+ref.Length]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
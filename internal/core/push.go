@@ -0,0 +1,99 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Push uploads every file under localPath (a single file or a directory
+// tree) using the chunked, deduplicated pack format from UploadChunked,
+// and records the result as a new commit.
+func (m *ArtifactManager) Push(localPath string) (string, error) {
+	manifest := &Manifest{}
+
+	// A single pack session is shared across every file in this push, so
+	// chunks from many small files are consolidated into shared ~16MB
+	// packs (and the chunk index is loaded/saved once) instead of each
+	// file flushing its own pack.
+	session, err := newPackSession(m.repo)
+	if err != nil {
+		return "", fmt.Errorf("push: %w", err)
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return "", fmt.Errorf("push: %w", err)
+	}
+
+	if !info.IsDir() {
+		f, err := pushFile(session, localPath, filepath.Base(localPath))
+		if err != nil {
+			return "", fmt.Errorf("push: %w", err)
+		}
+		manifest.Files = append(manifest.Files, f)
+	} else {
+		err := filepath.WalkDir(localPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(localPath, path)
+			if err != nil {
+				return err
+			}
+
+			f, err := pushFile(session, path, filepath.ToSlash(rel))
+			if err != nil {
+				return err
+			}
+			manifest.Files = append(manifest.Files, f)
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("push: %w", err)
+		}
+	}
+
+	if err := session.Close(); err != nil {
+		return "", fmt.Errorf("push: %w", err)
+	}
+
+	ref := fmt.Sprintf("%d", time.Now().UnixNano())
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return "", fmt.Errorf("push: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", "art-commit-*")
+	if err != nil {
+		return "", fmt.Errorf("push: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("push: %w", err)
+	}
+	tmp.Close()
+
+	if err := m.repo.Upload(tmp.Name(), commitManifestPath(ref), nil); err != nil {
+		return "", fmt.Errorf("push: %w", err)
+	}
+
+	return ref, nil
+}
+
+func pushFile(session *packSession, localPath, repoRelPath string) (ManifestFile, error) {
+	fileManifest, err := session.uploadFile(localPath)
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	return ManifestFile{Path: repoRelPath, FileManifest: *fileManifest}, nil
+}
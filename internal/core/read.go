@@ -0,0 +1,117 @@
+package core
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/infuseai/art/internal/repository"
+)
+
+// FileManifestAt returns the ManifestFile for repoRelPath within the
+// commit ref points at.
+func (m *ArtifactManager) FileManifestAt(ref, repoRelPath string) (*ManifestFile, error) {
+	manifest, err := m.loadManifest(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range manifest.Files {
+		if manifest.Files[i].Path == repoRelPath {
+			return &manifest.Files[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no such file %q in commit %s", repoRelPath, ref)
+}
+
+// ReadRange returns the size bytes of f starting at offset, fetching
+// only the pack blobs that back that range rather than downloading the
+// whole file - this is what lets mount serve a read without
+// materializing the entire (possibly huge) artifact first.
+func (m *ArtifactManager) ReadRange(f *ManifestFile, offset, size int64) ([]byte, error) {
+	return readChunkRange(m.repo, &f.FileManifest, offset, size)
+}
+
+// chunkSpan is the portion of a single chunk that overlaps a requested
+// byte range.
+type chunkSpan struct {
+	ref        ChunkRef
+	start, end int64
+}
+
+// overlappingChunks returns, in order, the chunks of manifest that
+// overlap [offset, offset+size), and the start/end within each chunk's
+// own bytes to keep.
+func overlappingChunks(manifest *FileManifest, offset, size int64) []chunkSpan {
+	var spans []chunkSpan
+	var pos int64
+
+	for _, ref := range manifest.Chunks {
+		chunkStart, chunkEnd := pos, pos+ref.Length
+		pos = chunkEnd
+
+		if chunkEnd <= offset || chunkStart >= offset+size {
+			continue
+		}
+
+		start := int64(0)
+		if offset > chunkStart {
+			start = offset - chunkStart
+		}
+		end := ref.Length
+		if chunkStart+end > offset+size {
+			end = offset + size - chunkStart
+		}
+		spans = append(spans, chunkSpan{ref: ref, start: start, end: end})
+
+		if chunkEnd >= offset+size {
+			break
+		}
+	}
+
+	return spans
+}
+
+func readChunkRange(repo repository.Repository, manifest *FileManifest, offset, size int64) ([]byte, error) {
+	// Like DownloadChunked, cache each pack within this one call: a
+	// requested range commonly spans several chunks backed by the same
+	// pack blob, and without this cache each of those chunks would
+	// re-download the identical pack.
+	packCache := map[string][]byte{}
+
+	var result []byte
+	for _, span := range overlappingChunks(manifest, offset, size) {
+		data, ok := packCache[span.ref.Pack]
+		if !ok {
+			fetched, err := fetchPack(repo, span.ref.Pack)
+			if err != nil {
+				return nil, err
+			}
+			data = fetched
+			packCache[span.ref.Pack] = data
+		}
+
+		if span.ref.Offset+span.ref.Length > int64(len(data)) {
+			return nil, fmt.Errorf("chunk out of range of pack %s", span.ref.Pack)
+		}
+		chunk := data[span.ref.Offset : span.ref.Offset+span.ref.Length]
+		result = append(result, chunk[span.start:span.end]...)
+	}
+	return result, nil
+}
+
+// fetchPack downloads and returns the full contents of the pack blob
+// packID, for the caller to slice chunks out of.
+func fetchPack(repo repository.Repository, packID string) ([]byte, error) {
+	tmp, err := os.CreateTemp("", "art-read-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	tmp.Close()
+
+	if err := repo.Download(blobPath(packID), tmp.Name(), nil); err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(tmp.Name())
+}
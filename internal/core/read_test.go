@@ -0,0 +1,67 @@
+package core
+
+import "testing"
+
+func TestOverlappingChunks(t *testing.T) {
+	// Three 10-byte chunks laid out back to back: [0,10) [10,20) [20,30).
+	manifest := &FileManifest{
+		Chunks: []ChunkRef{
+			{Digest: "a", Pack: "p", Offset: 0, Length: 10},
+			{Digest: "b", Pack: "p", Offset: 10, Length: 10},
+			{Digest: "c", Pack: "p", Offset: 20, Length: 10},
+		},
+	}
+
+	cases := []struct {
+		desc         string
+		offset, size int64
+		want         []struct {
+			digest     string
+			start, end int64
+		}
+	}{
+		{
+			desc: "within a single chunk", offset: 2, size: 3,
+			want: []struct {
+				digest     string
+				start, end int64
+			}{{"a", 2, 5}},
+		},
+		{
+			desc: "spans two chunks", offset: 8, size: 6,
+			want: []struct {
+				digest     string
+				start, end int64
+			}{{"a", 8, 10}, {"b", 0, 4}},
+		},
+		{
+			desc: "spans all three chunks", offset: 5, size: 20,
+			want: []struct {
+				digest     string
+				start, end int64
+			}{{"a", 5, 10}, {"b", 0, 10}, {"c", 0, 5}},
+		},
+		{
+			desc: "exactly one chunk", offset: 10, size: 10,
+			want: []struct {
+				digest     string
+				start, end int64
+			}{{"b", 0, 10}},
+		},
+	}
+
+	for _, tC := range cases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got := overlappingChunks(manifest, tC.offset, tC.size)
+			if len(got) != len(tC.want) {
+				t.Fatalf("got %d spans, want %d: %+v", len(got), len(tC.want), got)
+			}
+			for i, span := range got {
+				w := tC.want[i]
+				if span.ref.Digest != w.digest || span.start != w.start || span.end != w.end {
+					t.Errorf("span %d = {%s %d %d}, want {%s %d %d}", i, span.ref.Digest, span.start, span.end, w.digest, w.start, w.end)
+				}
+			}
+		})
+	}
+}
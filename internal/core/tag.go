@@ -0,0 +1,157 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// tagPath is where the small pointer manifest for a tag is stored in the
+// repository. list and mount resolve a tag the same way they resolve a
+// raw version string, by reading this file to find the commit ref it
+// points at.
+func tagPath(name string) string {
+	return fmt.Sprintf("tags/%s", name)
+}
+
+// validateTagName rejects names that would let a tag escape the tags/
+// namespace on a path-based backend, e.g. via "/" or "..".
+func validateTagName(name string) error {
+	if name == "" {
+		return fmt.Errorf("tag name must not be empty")
+	}
+	if strings.ContainsAny(name, "/\\") {
+		return fmt.Errorf("tag name %q must not contain a path separator", name)
+	}
+	if name == "." || name == ".." {
+		return fmt.Errorf("tag name %q is not allowed", name)
+	}
+	return nil
+}
+
+// Tag is a named, lightweight ref onto a commit, with enough metadata to
+// show who created it and why.
+type Tag struct {
+	Name      string    `json:"name"`
+	Ref       string    `json:"ref"`
+	Author    string    `json:"author,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AddTag points name at ref, overwriting any existing tag of that name.
+func (m *ArtifactManager) AddTag(name, ref, author, message string) error {
+	if err := validateTagName(name); err != nil {
+		return fmt.Errorf("tag add: %w", err)
+	}
+	resolved, err := m.ResolveRef(ref)
+	if err != nil {
+		return fmt.Errorf("tag add %s: %w", name, err)
+	}
+
+	tag := Tag{
+		Name:      name,
+		Ref:       resolved,
+		Author:    author,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+
+	data, err := json.Marshal(tag)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "art-tag-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	tmp.Close()
+
+	if err := m.repo.Upload(tmp.Name(), tagPath(name), nil); err != nil {
+		return fmt.Errorf("tag add %s: %w", name, err)
+	}
+	return nil
+}
+
+// RemoveTag deletes the tag named name. It is not an error to remove a
+// tag that does not exist.
+func (m *ArtifactManager) RemoveTag(name string) error {
+	if err := validateTagName(name); err != nil {
+		return fmt.Errorf("tag rm: %w", err)
+	}
+	if err := m.repo.Delete(tagPath(name)); err != nil {
+		return fmt.Errorf("tag rm %s: %w", name, err)
+	}
+	return nil
+}
+
+// ListTags returns every tag in the repository, sorted by the backend's
+// natural List() order.
+func (m *ArtifactManager) ListTags() ([]Tag, error) {
+	infos, err := m.repo.List("tags")
+	if err != nil {
+		return nil, fmt.Errorf("tag ls: %w", err)
+	}
+
+	tags := make([]Tag, 0, len(infos))
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+
+		tag, err := m.loadTag(info.Name())
+		if err != nil {
+			return nil, fmt.Errorf("tag ls: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (m *ArtifactManager) loadTag(name string) (Tag, error) {
+	tmp, err := os.CreateTemp("", "art-tag-*")
+	if err != nil {
+		return Tag{}, err
+	}
+	defer os.Remove(tmp.Name())
+	tmp.Close()
+
+	if err := m.repo.Download(tagPath(name), tmp.Name(), nil); err != nil {
+		return Tag{}, err
+	}
+
+	data, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return Tag{}, err
+	}
+
+	var tag Tag
+	if err := json.Unmarshal(data, &tag); err != nil {
+		return Tag{}, err
+	}
+	return tag, nil
+}
+
+// ResolveTag resolves a tag name to the commit ref it points at. It
+// returns an error if no tag of that name exists, so that callers
+// resolving a general ref string (core.RefLatest, a raw version, or a
+// tag) can fall through to treating it as a raw version string.
+func (m *ArtifactManager) ResolveTag(name string) (string, error) {
+	if err := validateTagName(name); err != nil {
+		return "", err
+	}
+
+	tag, err := m.loadTag(name)
+	if err != nil {
+		return "", fmt.Errorf("no such tag %q", name)
+	}
+	return tag.Ref, nil
+}
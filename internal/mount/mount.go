@@ -0,0 +1,170 @@
+//go:build linux || darwin
+
+// Package mount exposes a repository's committed versions as a read-only
+// FUSE filesystem: each top-level entry is a ref ("latest", a ref
+// returned by ListRefs, or a tag),
+// and beneath it the tree mirrors the artifact layout of that commit.
+package mount
+
+import (
+	"context"
+	"os"
+	"syscall"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+
+	"github.com/infuseai/art/internal/core"
+)
+
+// Mount blocks, serving repo as a read-only FUSE filesystem at mountpoint,
+// until the filesystem is unmounted or ctx is canceled.
+func Mount(ctx context.Context, mngr *core.ArtifactManager, mountpoint string) error {
+	c, err := fuse.Mount(mountpoint, fuse.ReadOnly(), fuse.FSName("artivc"), fuse.Subtype("artivcfs"))
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- fs.Serve(c, &root{mngr: mngr})
+	}()
+
+	select {
+	case <-ctx.Done():
+		return fuse.Unmount(mountpoint)
+	case err := <-errc:
+		return err
+	}
+}
+
+// root is the filesystem root: one directory entry per ref.
+type root struct {
+	mngr *core.ArtifactManager
+}
+
+func (r *root) Root() (fs.Node, error) {
+	return &refsDir{mngr: r.mngr}, nil
+}
+
+type refsDir struct {
+	mngr *core.ArtifactManager
+}
+
+func (d *refsDir) Attr(ctx context.Context, a *fuse.Attr) error {
+	a.Mode = os.ModeDir | 0555
+	return nil
+}
+
+func (d *refsDir) Lookup(ctx context.Context, name string) (fs.Node, error) {
+	// ResolveRef already falls back to tag lookup, so this also covers
+	// "mount/mytag" resolving to the same commit as "mount/v1.0.0".
+	ref, err := d.mngr.ResolveRef(name)
+	if err != nil {
+		return nil, syscall.ENOENT
+	}
+	return &versionDir{mngr: d.mngr, ref: ref, path: ""}, nil
+}
+
+func (d *refsDir) ReadDirAll(ctx context.Context) ([]fuse.Dirent, error) {
+	refs, err := d.mngr.ListRefs()
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := d.mngr.ListTags()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]fuse.Dirent, 0, len(refs)+len(tags)+1)
+	entries = append(entries, fuse.Dirent{Name: core.RefLatest, Type: fuse.DT_Dir})
+	for _, ref := range refs {
+		entries = append(entries, fuse.Dirent{Name: ref, Type: fuse.DT_Dir})
+	}
+	for _, tag := range tags {
+		entries = append(entries, fuse.Dirent{Name: tag.Name, Type: fuse.DT_Dir})
+	}
+	return entries, nil
+}
+
+// versionDir is a directory within one committed ref, identified by its
+// path relative to the artifact root.
+type versionDir struct {
+	mngr *core.ArtifactManager
+	ref  string
+	path string
+}
+
+func (d *versionDir) Attr(ctx context.Context, a *fuse.Attr) error {
+	a.Mode = os.ModeDir | 0555
+	return nil
+}
+
+func (d *versionDir) Lookup(ctx context.Context, name string) (fs.Node, error) {
+	childPath := joinPath(d.path, name)
+
+	info, err := d.mngr.StatAt(d.ref, childPath)
+	if err != nil {
+		return nil, syscall.ENOENT
+	}
+	if info.IsDir() {
+		return &versionDir{mngr: d.mngr, ref: d.ref, path: childPath}, nil
+	}
+
+	file, err := d.mngr.FileManifestAt(d.ref, childPath)
+	if err != nil {
+		return nil, syscall.ENOENT
+	}
+	return &versionFile{mngr: d.mngr, file: file, size: info.Size()}, nil
+}
+
+func (d *versionDir) ReadDirAll(ctx context.Context) ([]fuse.Dirent, error) {
+	infos, err := d.mngr.ListAt(d.ref, d.path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]fuse.Dirent, 0, len(infos))
+	for _, info := range infos {
+		typ := fuse.DT_File
+		if info.IsDir() {
+			typ = fuse.DT_Dir
+		}
+		entries = append(entries, fuse.Dirent{Name: info.Name(), Type: typ})
+	}
+	return entries, nil
+}
+
+// versionFile is a single committed file. It implements fs.HandleReader
+// rather than fs.HandleReadAller so a read only fetches the pack blobs
+// backing the requested byte range, instead of materializing the whole
+// (possibly huge) file on first touch.
+type versionFile struct {
+	mngr *core.ArtifactManager
+	file *core.ManifestFile
+	size int64
+}
+
+func (f *versionFile) Attr(ctx context.Context, a *fuse.Attr) error {
+	a.Mode = 0444
+	a.Size = uint64(f.size)
+	return nil
+}
+
+func (f *versionFile) Read(ctx context.Context, req *fuse.ReadRequest, resp *fuse.ReadResponse) error {
+	data, err := f.mngr.ReadRange(f.file, req.Offset, int64(req.Size))
+	if err != nil {
+		return err
+	}
+	resp.Data = data
+	return nil
+}
+
+func joinPath(dir, name string) string {
+	if dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
@@ -1,28 +1,28 @@
 // Run integration test to any repository
 //
 // TEST_REPOSITORY=s3://bucket/myrepo go test -v ./internal/repository
-package repository
+package repository_test
 
 import (
 	cryptorand "crypto/rand"
 	"crypto/sha1"
 	"fmt"
 	"io"
-	"math/rand"
 	"os"
-	"path/filepath"
 	"testing"
-	"time"
 
+	"github.com/infuseai/art/internal/core"
+	"github.com/infuseai/art/internal/repository"
+	repotest "github.com/infuseai/art/internal/repository/test"
 	"github.com/stretchr/testify/assert"
 )
 
-func getRepo() (Repository, error) {
+func getRepo() (repository.Repository, error) {
 	repoStr := os.Getenv("TEST_REPOSITORY")
 	if repoStr == "" {
 		return nil, nil
 	}
-	return NewRepository(repoStr)
+	return repository.NewRepository(repoStr)
 }
 
 func sha1sum(path string) string {
@@ -54,181 +54,101 @@ func generateRandomFile(path string, size int64) error {
 	return nil
 }
 
-func Test_Transfer(t *testing.T) {
+// Test_Repository runs the shared repository conformance suite against
+// whatever backend TEST_REPOSITORY points at.
+func Test_Repository(t *testing.T) {
 	repo, err := getRepo()
 	if repo == nil {
 		return
 	}
-
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 
-	testCases := []struct {
-		desc     string
-		size     int64
-		repoPath string
-	}{
-		{desc: "small file", size: 1024, repoPath: "bin"},
-		{desc: "small file with subpath", size: 1024, repoPath: "this/is/my/bin"},
-		{desc: "large file", size: 10 * 1024 * 1024, repoPath: "bin"},
-		{desc: "empty file", size: 0, repoPath: "bin"},
-	}
-	for _, tC := range testCases {
-		t.Run(tC.desc, func(t *testing.T) {
-			tmpDir := t.TempDir()
-			path := tmpDir + "/in"
-			generateRandomFile(path, tC.size)
-
-			err = repo.Upload(path, tC.repoPath, nil)
-			if err != nil {
-				t.Error(err)
-			}
-
-			repo.Download(tC.repoPath, tmpDir+"/out", nil)
-			if err != nil {
-				t.Error(err)
-			}
-
-			assert.Equal(t, sha1sum(tmpDir+"/in"), sha1sum(tmpDir+"/out"))
-
-			err = repo.Delete(tC.repoPath)
-			if err != nil {
-				t.Error(err)
-			}
-		})
-	}
+	repotest.RunSuite(t, func() (repository.Repository, error) {
+		return getRepo()
+	})
 }
-func Test_Stat(t *testing.T) {
+
+// Test_ChunkedPackLayout exercises core.UploadChunked/DownloadChunked
+// against the repository, and checks that chunks land in fan-out
+// "data/xx/..." pack blobs rather than as one object per file.
+func Test_ChunkedPackLayout(t *testing.T) {
 	repo, err := getRepo()
 	if repo == nil {
 		return
 	}
-
 	if err != nil {
 		t.Error(err)
 	}
 
-	rand.Seed(time.Now().UnixNano())
 	tmpDir := t.TempDir()
-	path := tmpDir + "/bin"
-	repoPath := fmt.Sprintf("stat/%d", rand.Int())
-
-	// stat non-existed file
-	_, err = repo.Stat(repoPath)
-	assert.Error(t, err, "Stat() should return error if the file does not exist")
+	path := tmpDir + "/in"
+	// Large enough to span multiple chunks at the 512KB/1MB/8MB target
+	// sizes, so at least one pack blob is produced.
+	generateRandomFile(path, 4*1024*1024)
 
-	// upload & stat
-	generateRandomFile(path, 1024)
-	err = repo.Upload(path, repoPath, nil)
+	manifest, err := core.UploadChunked(repo, path)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
+	assert.NotEmpty(t, manifest.Chunks)
 
-	info, err := repo.Stat(repoPath)
-	if err != nil {
-		t.Error(err)
+	for _, ref := range manifest.Chunks {
+		assert.NotEmpty(t, ref.Pack)
+
+		info, err := repo.Stat(fmt.Sprintf("data/%s/%s", ref.Pack[:2], ref.Pack))
+		if err != nil {
+			t.Errorf("pack blob for chunk %s missing: %v", ref.Digest, err)
+			continue
+		}
+		assert.False(t, info.IsDir())
 	}
-	assert.Equal(t, filepath.Base(repoPath), info.Name(), "name of Stat() should be the last component of path")
-	assert.Equal(t, false, info.IsDir(), "result of Stat() should not be a directory ")
 
-	// delete
-	err = repo.Delete(repoPath)
+	list, err := repo.List("data")
 	if err != nil {
 		t.Error(err)
 	}
+	assert.NotEmpty(t, list, "chunked upload should have produced at least one data/xx fan-out directory")
 
-	_, err = repo.Stat(repoPath)
-	assert.Error(t, err, "Stat() should return error after the file deleted")
+	out := tmpDir + "/out"
+	if err := core.DownloadChunked(repo, manifest, out); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, sha1sum(path), sha1sum(out))
 }
 
-func Test_List(t *testing.T) {
+// Test_Check uploads a chunked file, deletes one of its pack blobs, and
+// asserts that core.CheckManifest reports the blob as missing.
+func Test_Check(t *testing.T) {
 	repo, err := getRepo()
 	if repo == nil {
 		return
 	}
-
 	if err != nil {
 		t.Error(err)
 	}
 
-	rand.Seed(time.Now().UnixNano())
 	tmpDir := t.TempDir()
-	path := tmpDir + "/bin"
-	generateRandomFile(path, 1024)
-
-	// Create files
-	//
-	// dir
-	// ├── 0
-	// ├── 1
-	// ├── 2
-	// └── 3
-	// 	   ├── 0
-	// 	   ├── 1
-	// 	   └── 2
-	for i := 0; i < 3; i++ {
-		rpath := fmt.Sprintf("dir/%d", i)
-		err = repo.Upload(path, rpath, nil)
-		if err != nil {
-			t.Error(err)
-		}
+	path := tmpDir + "/in"
+	generateRandomFile(path, 4*1024*1024)
 
-		defer repo.Delete(rpath)
+	manifest, err := core.UploadChunked(repo, path)
+	if err != nil {
+		t.Fatal(err)
 	}
-	for i := 0; i < 3; i++ {
-		rpath := fmt.Sprintf("dir/3/%d", i)
 
-		err = repo.Upload(path, rpath, nil)
-		if err != nil {
-			t.Error(err)
-		}
-
-		defer repo.Delete(rpath)
-	}
-
-	// test
-	// ls dir
-	list, err := repo.List("dir")
-	assert.Equal(t, 4, len(list))
-	for _, info := range list {
-		switch info.Name() {
-		case "0":
-			assert.False(t, info.IsDir())
-		case "1":
-			assert.False(t, info.IsDir())
-		case "2":
-			assert.False(t, info.IsDir())
-		case "3":
-			assert.True(t, info.IsDir())
-		default:
-			assert.Fail(t, "wrong list item")
-		}
-	}
+	// A healthy manifest should check clean.
+	errs := core.CheckManifest(repo, manifest, core.CheckOptions{})
+	assert.Empty(t, errs)
 
-	// ls dir/3
-	list, err = repo.List("dir/3")
+	// Delete the pack backing the first chunk, and confirm Check notices.
+	missingPack := manifest.Chunks[0].Pack
+	err = repo.Delete(fmt.Sprintf("data/%s/%s", missingPack[:2], missingPack))
 	if err != nil {
-		t.Error(err)
-	}
-	assert.Equal(t, 3, len(list))
-	for _, info := range list {
-		switch info.Name() {
-		case "0":
-			assert.False(t, info.IsDir())
-		case "1":
-			assert.False(t, info.IsDir())
-		case "2":
-			assert.False(t, info.IsDir())
-		default:
-			assert.Fail(t, "wrong list item")
-		}
+		t.Fatal(err)
 	}
 
-	// ls nono-existing folder
-	list, err = repo.List("dir-12345")
-	if err != nil {
-		t.Error(err)
-	}
-	assert.Equal(t, 0, len(list))
+	errs = core.CheckManifest(repo, manifest, core.CheckOptions{})
+	assert.NotEmpty(t, errs, "Check should report the deleted pack as missing")
 }
@@ -0,0 +1,402 @@
+// Package repotest is a conformance suite for implementations of
+// repository.Repository, modeled on go-git's plumbing/transport/test
+// suite. Any new backend (S3, GCS, local, or a future Azure/B2/SFTP/HTTP
+// backend) can be checked against the same contract the existing
+// backends satisfy with a single call:
+//
+//	func TestMyBackend(t *testing.T) {
+//		repotest.RunSuite(t, func() (repository.Repository, error) {
+//			return NewMyBackend(...)
+//		})
+//	}
+package repotest
+
+import (
+	cryptorand "crypto/rand"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infuseai/art/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// NewRepositoryFunc constructs a fresh, empty Repository for a test to
+// run against. The suite calls it once per subtest so backends that keep
+// server-side state (e.g. an object prefix per test) can isolate runs.
+type NewRepositoryFunc func() (repository.Repository, error)
+
+// RunSuite runs the full conformance suite against the repository
+// returned by newRepo, as subtests of t.
+func RunSuite(t *testing.T, newRepo NewRepositoryFunc) {
+	t.Run("Transfer", func(t *testing.T) { testTransfer(t, newRepo) })
+	t.Run("Stat", func(t *testing.T) { testStat(t, newRepo) })
+	t.Run("List", func(t *testing.T) { testList(t, newRepo) })
+	t.Run("ConcurrentUploads", func(t *testing.T) { testConcurrentUploads(t, newRepo) })
+	t.Run("DownloadOverwritesExisting", func(t *testing.T) { testDownloadOverwritesExisting(t, newRepo) })
+	t.Run("ListPagination", func(t *testing.T) { testListPagination(t, newRepo) })
+	t.Run("StatDirVsFile", func(t *testing.T) { testStatDirVsFile(t, newRepo) })
+	t.Run("DeleteMissing", func(t *testing.T) { testDeleteMissing(t, newRepo) })
+	t.Run("LargeFileStreaming", func(t *testing.T) { testLargeFileStreaming(t, newRepo) })
+}
+
+func sha1sum(path string) string {
+	hasher := sha1.New()
+	f, err := os.Open(path)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(hasher, f); err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("%x", hasher.Sum(nil))
+}
+
+func generateRandomFile(path string, size int64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.CopyN(f, cryptorand.Reader, size)
+	return err
+}
+
+func testTransfer(t *testing.T, newRepo NewRepositoryFunc) {
+	repo, err := newRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc     string
+		size     int64
+		repoPath string
+	}{
+		{desc: "small file", size: 1024, repoPath: "bin"},
+		{desc: "small file with subpath", size: 1024, repoPath: "this/is/my/bin"},
+		{desc: "large file", size: 10 * 1024 * 1024, repoPath: "bin"},
+		{desc: "empty file", size: 0, repoPath: "bin"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			path := tmpDir + "/in"
+			generateRandomFile(path, tC.size)
+
+			if err := repo.Upload(path, tC.repoPath, nil); err != nil {
+				t.Error(err)
+			}
+
+			if err := repo.Download(tC.repoPath, tmpDir+"/out", nil); err != nil {
+				t.Error(err)
+			}
+
+			assert.Equal(t, sha1sum(tmpDir+"/in"), sha1sum(tmpDir+"/out"))
+
+			if err := repo.Delete(tC.repoPath); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func testStat(t *testing.T, newRepo NewRepositoryFunc) {
+	repo, err := newRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rand.Seed(time.Now().UnixNano())
+	tmpDir := t.TempDir()
+	path := tmpDir + "/bin"
+	repoPath := fmt.Sprintf("stat/%d", rand.Int())
+
+	// stat non-existed file
+	_, err = repo.Stat(repoPath)
+	assert.Error(t, err, "Stat() should return error if the file does not exist")
+
+	// upload & stat
+	generateRandomFile(path, 1024)
+	if err := repo.Upload(path, repoPath, nil); err != nil {
+		t.Error(err)
+	}
+
+	info, err := repo.Stat(repoPath)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, filepath.Base(repoPath), info.Name(), "name of Stat() should be the last component of path")
+	assert.Equal(t, false, info.IsDir(), "result of Stat() should not be a directory ")
+
+	if err := repo.Delete(repoPath); err != nil {
+		t.Error(err)
+	}
+
+	_, err = repo.Stat(repoPath)
+	assert.Error(t, err, "Stat() should return error after the file deleted")
+}
+
+func testList(t *testing.T, newRepo NewRepositoryFunc) {
+	repo, err := newRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rand.Seed(time.Now().UnixNano())
+	tmpDir := t.TempDir()
+	path := tmpDir + "/bin"
+	generateRandomFile(path, 1024)
+
+	// dir
+	// ├── 0
+	// ├── 1
+	// ├── 2
+	// └── 3
+	// 	   ├── 0
+	// 	   ├── 1
+	// 	   └── 2
+	for i := 0; i < 3; i++ {
+		rpath := fmt.Sprintf("dir/%d", i)
+		if err := repo.Upload(path, rpath, nil); err != nil {
+			t.Error(err)
+		}
+		defer repo.Delete(rpath)
+	}
+	for i := 0; i < 3; i++ {
+		rpath := fmt.Sprintf("dir/3/%d", i)
+		if err := repo.Upload(path, rpath, nil); err != nil {
+			t.Error(err)
+		}
+		defer repo.Delete(rpath)
+	}
+
+	list, err := repo.List("dir")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, 4, len(list))
+	for _, info := range list {
+		switch info.Name() {
+		case "0", "1", "2":
+			assert.False(t, info.IsDir())
+		case "3":
+			assert.True(t, info.IsDir())
+		default:
+			assert.Fail(t, "wrong list item")
+		}
+	}
+
+	list, err = repo.List("dir/3")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, 3, len(list))
+	for _, info := range list {
+		switch info.Name() {
+		case "0", "1", "2":
+			assert.False(t, info.IsDir())
+		default:
+			assert.Fail(t, "wrong list item")
+		}
+	}
+
+	list, err = repo.List("dir-12345")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, 0, len(list))
+}
+
+// testConcurrentUploads uploads many distinct paths from goroutines at
+// once, and checks every one lands intact - a backend that isn't safe
+// for concurrent use (e.g. a shared http.Client misconfigured, or a
+// local-filesystem backend that doesn't mkdir -p safely) will flake here.
+func testConcurrentUploads(t *testing.T, newRepo NewRepositoryFunc) {
+	repo, err := newRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 16
+	tmpDir := t.TempDir()
+
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	sums := make([]string, n)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			path := fmt.Sprintf("%s/in-%d", tmpDir, i)
+			if err := generateRandomFile(path, 4096); err != nil {
+				errs[i] = err
+				return
+			}
+			sums[i] = sha1sum(path)
+			errs[i] = repo.Upload(path, fmt.Sprintf("concurrent/%d", i), nil)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < n; i++ {
+		if errs[i] != nil {
+			t.Errorf("upload %d: %v", i, errs[i])
+			continue
+		}
+
+		out := fmt.Sprintf("%s/out-%d", tmpDir, i)
+		repoPath := fmt.Sprintf("concurrent/%d", i)
+		if err := repo.Download(repoPath, out, nil); err != nil {
+			t.Errorf("download %d: %v", i, err)
+			continue
+		}
+		assert.Equal(t, sums[i], sha1sum(out))
+		defer repo.Delete(repoPath)
+	}
+}
+
+// testDownloadOverwritesExisting downloads into a destination that already
+// contains stale data, to make sure Download fully overwrites rather
+// than appending to or merging with existing content.
+func testDownloadOverwritesExisting(t *testing.T, newRepo NewRepositoryFunc) {
+	repo, err := newRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	in := tmpDir + "/in"
+	generateRandomFile(in, 2*1024*1024)
+
+	if err := repo.Upload(in, "partial", nil); err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Delete("partial")
+
+	out := tmpDir + "/out"
+	// Pre-seed the destination with more data than the real download so
+	// a naive implementation that doesn't truncate would leave a trailer.
+	generateRandomFile(out, 4*1024*1024)
+
+	if err := repo.Download("partial", out, nil); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, sha1sum(in), sha1sum(out))
+}
+
+// testListPagination uploads more entries than a single page of most
+// object-store list APIs (1000) and checks List still returns them all.
+func testListPagination(t *testing.T, newRepo NewRepositoryFunc) {
+	if testing.Short() {
+		t.Skip("skipping pagination test in -short mode")
+	}
+
+	repo, err := newRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	path := tmpDir + "/bin"
+	generateRandomFile(path, 16)
+
+	const n = 1200
+	for i := 0; i < n; i++ {
+		rpath := fmt.Sprintf("paged/%05d", i)
+		if err := repo.Upload(path, rpath, nil); err != nil {
+			t.Fatal(err)
+		}
+		defer repo.Delete(rpath)
+	}
+
+	list, err := repo.List("paged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, n, len(list), "List() should paginate past a single backend page")
+}
+
+// testStatDirVsFile checks Stat correctly distinguishes a directory
+// prefix from a leaf file beneath it.
+func testStatDirVsFile(t *testing.T, newRepo NewRepositoryFunc) {
+	repo, err := newRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	path := tmpDir + "/bin"
+	generateRandomFile(path, 16)
+
+	if err := repo.Upload(path, "tree/leaf", nil); err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Delete("tree/leaf")
+
+	dirInfo, err := repo.Stat("tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, dirInfo.IsDir(), "Stat() on a directory prefix should report IsDir()")
+
+	fileInfo, err := repo.Stat("tree/leaf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, fileInfo.IsDir(), "Stat() on a leaf file should not report IsDir()")
+}
+
+// testDeleteMissing checks the documented semantics for deleting a path
+// that doesn't exist: backends in this repo treat it as a no-op rather
+// than an error, matching how a repeated `art prune` run behaves.
+func testDeleteMissing(t *testing.T, newRepo NewRepositoryFunc) {
+	repo, err := newRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.Delete("does/not/exist")
+	assert.NoError(t, err, "Delete() of a missing path should be a no-op, not an error")
+}
+
+// testLargeFileStreaming uploads and downloads a file bigger than most
+// in-memory buffering would comfortably hold, to catch backends that
+// load the whole object into memory instead of streaming it.
+func testLargeFileStreaming(t *testing.T, newRepo NewRepositoryFunc) {
+	if testing.Short() {
+		t.Skip("skipping large-file test in -short mode")
+	}
+
+	repo, err := newRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	in := tmpDir + "/in"
+	if err := generateRandomFile(in, 110*1024*1024); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Upload(in, "large", nil); err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Delete("large")
+
+	out := tmpDir + "/out"
+	if err := repo.Download("large", out, nil); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, sha1sum(in), sha1sum(out))
+}